@@ -30,6 +30,36 @@ func NewHasher64(hf func() hash.Hash64) Hasher {
 	}
 }
 
+// NewHasher uses a generic hash.Hash constructor, such as sha256.New or
+// blake2b.New256, as the basis for the returned Hasher implementation.  The hash's
+// Sum is reduced to a uint64 by reading its first 8 bytes as a big-endian integer,
+// truncating longer sums and padding shorter ones with trailing zero bytes.
+//
+// Key bytes are written before the member, the same order as NewHasher32 and
+// NewHasher64: the member itself is first reduced to its own digest via
+// memberDigest, and it's that digest, not the raw member bytes, that's written
+// second.  This indirection is what lets PrecomputeMembers cache the expensive part
+// -- hashing a member's (potentially long) bytes -- once per member, while every
+// Hasher still writes key before member; see precompute.go.
+func NewHasher(hf func() hash.Hash) Hasher {
+	return func(key, member []byte) uint64 {
+		h := hf()
+		h.Write(key)
+		h.Write(memberDigest(hf, member))
+		return sumToUint64(h.Sum(nil))
+	}
+}
+
+// memberDigest reduces member to a fixed-size digest under hf, run independently of
+// any key.  NewHasher and restoreHasher both combine this digest with a key
+// afterward, rather than the member's raw bytes, so PrecomputeMembers can compute it
+// once per member instead of on every lookup.
+func memberDigest(hf func() hash.Hash, member []byte) []byte {
+	h := hf()
+	h.Write(member)
+	return h.Sum(nil)
+}
+
 // DefaultHasher is the default hash implementation, which uses a FNV-1 64a hasher under the covers.
 func DefaultHasher(key, member []byte) uint64 {
 	h := fnv.New64a()
@@ -55,17 +85,31 @@ type Entry struct {
 type Hash struct {
 	entries []Entry
 	hasher  Hasher
+
+	// hasherWriter is set when hasher was built from a hash.Hash-based constructor
+	// (Hash32, Hash64, or Hash), letting the typed Get* methods stream a key into the
+	// hasher instead of first allocating a joined []byte.  It is nil whenever that
+	// can't be assumed, e.g. when a raw Hasher is supplied directly via Hasher.
+	hasherWriter HasherWriter
+
+	// tree is non-nil only for Hash instances created by Builder.Skeleton, in which
+	// case entries is unused and lookups are delegated to the tree instead.
+	tree *skeletonTree
 }
 
 // Len returns the number of entries in the rendezvous hash table.  If this method
 // returns 0, all methods that return members will return nil.
 func (h *Hash) Len() int {
+	if h.tree != nil {
+		return h.tree.count
+	}
+
 	return len(h.entries)
 }
 
 // Get returns the result of a rendezvous hash given an arbitrary key
 func (h *Hash) Get(key []byte) interface{} {
-	if len(h.entries) == 0 {
+	if h.Len() == 0 {
 		return nil
 	}
 
@@ -74,7 +118,7 @@ func (h *Hash) Get(key []byte) interface{} {
 
 // GetString returns the result of a rendezvous hash using a string key
 func (h *Hash) GetString(key string) interface{} {
-	if len(h.entries) == 0 {
+	if h.Len() == 0 {
 		// be kind to the gc: avoid an extra byte slice if we're empty anyway
 		return nil
 	}
@@ -83,6 +127,10 @@ func (h *Hash) GetString(key string) interface{} {
 }
 
 func (h *Hash) get(key []byte) interface{} {
+	if h.tree != nil {
+		return h.tree.get(key)
+	}
+
 	var (
 		champion interface{}
 		value    uint64
@@ -98,7 +146,77 @@ func (h *Hash) get(key []byte) interface{} {
 	return champion
 }
 
-var emptyHash = Hash{hasher: DefaultHasher}
+// GetN returns the top n members for the given key, in descending score order.  This
+// is useful for replica placement and consistent caching with fallbacks, since the
+// member ranked lowest is the first one to drop out as members are added or removed.
+//
+// If n is greater than or equal to Len(), all members are returned in score order.
+// If n <= 0, GetN returns nil.  GetN is only supported for a flat Hash; it always
+// returns nil for a Hash created by Builder.Skeleton.
+func (h *Hash) GetN(key []byte, n int) []interface{} {
+	if n <= 0 || len(h.entries) == 0 {
+		return nil
+	}
+
+	return h.getN(key, n)
+}
+
+// GetStringN is the same as GetN, but accepts a string key.
+func (h *Hash) GetStringN(key string, n int) []interface{} {
+	if n <= 0 || len(h.entries) == 0 {
+		return nil
+	}
+
+	return h.getN([]byte(key), n)
+}
+
+// ranked is a single slot in the small ordered ring maintained by getN.
+type ranked struct {
+	member interface{}
+	value  uint64
+}
+
+func (h *Hash) getN(key []byte, n int) []interface{} {
+	if n > len(h.entries) {
+		n = len(h.entries)
+	}
+
+	// ring holds, at all times, the top len(ring) scores seen so far in descending
+	// order.  n is typically small (2-5, for replica counts), so an insertion sort
+	// is cheaper than a full sort over all the entries.
+	ring := make([]ranked, 0, n)
+
+	for _, e := range h.entries {
+		v := h.hasher(key, e.Value)
+
+		if len(ring) == n && v <= ring[len(ring)-1].value {
+			continue
+		}
+
+		i := len(ring)
+		if i == n {
+			i--
+		} else {
+			ring = append(ring, ranked{})
+		}
+
+		for i > 0 && ring[i-1].value < v {
+			ring[i] = ring[i-1]
+			i--
+		}
+
+		ring[i] = ranked{member: e.Member, value: v}
+	}
+
+	members := make([]interface{}, len(ring))
+	for i, r := range ring {
+		members[i] = r.member
+	}
+
+	return members
+}
+
+var emptyHash = Hash{hasher: DefaultHasher, hasherWriter: defaultHasherWriter}
 
 // EmptyHash returns the canonicalized empty Hash instance.  This is used mainly by
 // the builder when no entries have been added.
@@ -109,25 +227,58 @@ func EmptyHash() *Hash {
 // Builder is a mutable, fluent builder for Hash instances.  Builders are not safe
 // for concurrent reads and writes.  The zero value for this struct is a valid instance.
 type Builder struct {
-	entries []Entry
-	hasher  Hasher
+	entries      []Entry
+	hasher       Hasher
+	hasherWriter HasherWriter
+	hf           func() hash.Hash
+	precompute   bool
 }
 
 // Hasher sets the Hasher strategy for the next Hash created by this builder.
-// By default, DefaultHasher is used.
+// By default, DefaultHasher is used.  Since an arbitrary Hasher can't be assumed to
+// support HasherWriter, this clears any HasherWriter set by a prior call to Hash32,
+// Hash64, or Hash, along with the hash.Hash constructor a prior call to Hash set for
+// PrecomputeMembers.
 func (b *Builder) Hasher(h Hasher) *Builder {
 	b.hasher = h
+	b.hasherWriter = nil
+	b.hf = nil
 	return b
 }
 
 // Hash32 uses a 32-bit hashing constructor as the hash algorithm
 func (b *Builder) Hash32(hf func() hash.Hash32) *Builder {
-	return b.Hasher(NewHasher32(hf))
+	b.Hasher(NewHasher32(hf))
+	b.hasherWriter = NewHasherWriter32(hf)
+	return b
 }
 
 // Hash64 uses a 64-bit hashing constructor as the hash algorithm
 func (b *Builder) Hash64(hf func() hash.Hash64) *Builder {
-	return b.Hasher(NewHasher64(hf))
+	b.Hasher(NewHasher64(hf))
+	b.hasherWriter = NewHasherWriter64(hf)
+	return b
+}
+
+// Hash uses a generic hash.Hash constructor, such as sha256.New or blake2b.New256, as
+// the hash algorithm.  This is the only hashing constructor that PrecomputeMembers
+// can take advantage of.
+func (b *Builder) Hash(hf func() hash.Hash) *Builder {
+	b.Hasher(NewHasher(hf))
+	b.hf = hf
+	b.hasherWriter = NewHasherWriter(hf)
+	return b
+}
+
+// PrecomputeMembers arranges for the next Hash created by this builder to reduce
+// each member to its digest once at build time, rather than rehashing the member's
+// (potentially much longer) bytes on every lookup.  Since the digests are stored
+// alongside the entries, this is opt-in: the memory cost scales with the member
+// count.  It takes effect only in New, and only when the hash algorithm was set by
+// Hash; otherwise New builds the usual Hash and Skeleton ignores it.
+func (b *Builder) PrecomputeMembers() *Builder {
+	b.precompute = true
+	return b
 }
 
 // Add appends entries to the final Hash
@@ -159,15 +310,66 @@ func (b *Builder) New() *Hash {
 	}
 
 	h := &Hash{
-		entries: b.entries,
-		hasher:  b.hasher,
+		entries:      b.entries,
+		hasher:       b.hasher,
+		hasherWriter: b.hasherWriter,
 	}
 
 	if h.hasher == nil {
 		h.hasher = DefaultHasher
+		h.hasherWriter = defaultHasherWriter
+	}
+
+	if b.precompute && b.hf != nil {
+		h.entries = precomputeEntries(b.entries, b.hf)
+		h.hasher = restoreHasher(b.hf)
+
+		// the precomputed digest is keyed by member, not by the key being looked
+		// up, so the writer fast path no longer applies
+		h.hasherWriter = nil
+	}
+
+	b.entries = nil
+	b.hasher = nil
+	b.hasherWriter = nil
+	b.hf = nil
+	b.precompute = false
+	return h
+}
+
+// Skeleton creates a Hash using this Builder's current configuration, backed by a
+// skeleton tree of the given fanout instead of a flat entry list.  Where the flat Hash
+// scans every member on each lookup, a skeleton-backed Hash descends the tree, paying
+// roughly fanout*log(fanout, n) hasher calls instead of a full O(n) scan.  This only
+// pays off once the member count reaches into the thousands; for smaller sets, prefer
+// New.  GetN and GetStringN are not supported on a skeleton-backed Hash; they always
+// return nil.
+//
+// fanout values below 2 are treated as 2.  This builder is reset prior to returning,
+// just like New.
+func (b *Builder) Skeleton(fanout int) *Hash {
+	if len(b.entries) == 0 {
+		return EmptyHash()
+	}
+
+	if fanout < 2 {
+		fanout = 2
+	}
+
+	hasher := b.hasher
+	if hasher == nil {
+		hasher = DefaultHasher
+	}
+
+	h := &Hash{
+		hasher: hasher,
+		tree:   newSkeletonTree(b.entries, hasher, fanout),
 	}
 
 	b.entries = nil
 	b.hasher = nil
+	b.hasherWriter = nil
+	b.hf = nil
+	b.precompute = false
 	return h
 }
@@ -3,6 +3,8 @@ package rendezvous
 import (
 	"fmt"
 	"hash/fnv"
+	"reflect"
+	"testing"
 )
 
 func ExampleHash() {
@@ -13,3 +15,46 @@ func ExampleHash() {
 	fmt.Println(h.GetString("mac:112233445566"))
 	// Output: bar.net
 }
+
+func ExampleHash_GetStringN() {
+	h := new(Builder).
+		Hash32(fnv.New32a).
+		AddStrings("foo.com", "bar.net", "baz.org").New()
+
+	fmt.Println(h.GetStringN("mac:112233445566", 2))
+	// Output: [bar.net baz.org]
+}
+
+func TestHashGetN(t *testing.T) {
+	h := new(Builder).
+		AddStrings("foo.com", "bar.net", "baz.org", "quux.io").New()
+
+	key := []byte("mac:112233445566")
+
+	full := h.GetN(key, h.Len())
+	if len(full) != h.Len() {
+		t.Fatalf("expected %d members, got %d", h.Len(), len(full))
+	}
+
+	top1 := h.GetN(key, 1)
+	if len(top1) != 1 || top1[0] != full[0] {
+		t.Errorf("GetN(key, 1) = %v, expected [%v]", top1, full[0])
+	}
+
+	top2 := h.GetN(key, 2)
+	if !reflect.DeepEqual(top2, full[:2]) {
+		t.Errorf("GetN(key, 2) = %v, expected %v", top2, full[:2])
+	}
+
+	if members := h.GetN(key, h.Len()+10); !reflect.DeepEqual(members, full) {
+		t.Errorf("GetN(key, n > Len()) = %v, expected %v", members, full)
+	}
+
+	if members := h.GetN(key, 0); members != nil {
+		t.Errorf("GetN(key, 0) = %v, expected nil", members)
+	}
+
+	if members := h.GetN(key, -1); members != nil {
+		t.Errorf("GetN(key, -1) = %v, expected nil", members)
+	}
+}
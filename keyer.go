@@ -0,0 +1,140 @@
+package rendezvous
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"io"
+	"sync"
+)
+
+// HasherWriter is an optional, richer contract than Hasher: rather than requiring a
+// key as a single pre-assembled []byte, it streams the key into the underlying hasher
+// via write.  Hash's typed Get* methods (GetInt64, GetUint64, GetBytes, GetFunc) use
+// this when the configured Hasher supports it, so that composite keys (e.g. tenant ID
+// + object ID + epoch) can be hashed without first joining them into one allocation.
+//
+// NewHasherWriter32, NewHasherWriter64, and NewHasherWriter build HasherWriter
+// implementations to go with NewHasher32, NewHasher64, and NewHasher, respectively.
+type HasherWriter interface {
+	// HashWriter hashes member against a key streamed via write, producing the same
+	// result as the equivalent Hasher given the fully assembled key bytes.
+	HashWriter(write func(io.Writer), member []byte) uint64
+}
+
+// hasherWriterFunc is a function adapter for HasherWriter, following the same
+// pattern as Hasher.
+type hasherWriterFunc func(write func(io.Writer), member []byte) uint64
+
+func (f hasherWriterFunc) HashWriter(write func(io.Writer), member []byte) uint64 {
+	return f(write, member)
+}
+
+// NewHasherWriter32 is the HasherWriter counterpart to NewHasher32.
+func NewHasherWriter32(hf func() hash.Hash32) HasherWriter {
+	return hasherWriterFunc(func(write func(io.Writer), member []byte) uint64 {
+		h := hf()
+		write(h)
+		h.Write(member)
+		return uint64(h.Sum32())
+	})
+}
+
+// NewHasherWriter64 is the HasherWriter counterpart to NewHasher64.
+func NewHasherWriter64(hf func() hash.Hash64) HasherWriter {
+	return hasherWriterFunc(func(write func(io.Writer), member []byte) uint64 {
+		h := hf()
+		write(h)
+		h.Write(member)
+		return h.Sum64()
+	})
+}
+
+// NewHasherWriter is the HasherWriter counterpart to NewHasher.  Like NewHasher, key
+// bytes are written first, followed by the member's digest rather than its raw
+// bytes.
+func NewHasherWriter(hf func() hash.Hash) HasherWriter {
+	return hasherWriterFunc(func(write func(io.Writer), member []byte) uint64 {
+		h := hf()
+		write(h)
+		h.Write(memberDigest(hf, member))
+		return sumToUint64(h.Sum(nil))
+	})
+}
+
+// defaultHasherWriter is the HasherWriter counterpart to DefaultHasher.
+var defaultHasherWriter = NewHasherWriter64(fnv.New64a)
+
+// keyBufferPool recycles the buffers used to assemble a key when a Hash's Hasher
+// doesn't implement HasherWriter.
+var keyBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// GetFunc returns the result of a rendezvous hash using a key streamed via write,
+// instead of a pre-assembled []byte.  This is the primitive the other typed Get*
+// methods are built on; use it directly to compose a key from several typed fields
+// (e.g. a tenant ID, an object ID, and an epoch) without allocating a joined []byte.
+//
+// If the Hash's Hasher doesn't implement HasherWriter, GetFunc falls back to
+// buffering the key into a pooled []byte.
+func (h *Hash) GetFunc(write func(io.Writer)) interface{} {
+	if h.Len() == 0 {
+		return nil
+	}
+
+	if h.hasherWriter != nil {
+		return h.getWriter(write)
+	}
+
+	buf := keyBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	write(buf)
+	result := h.get(buf.Bytes())
+	keyBufferPool.Put(buf)
+	return result
+}
+
+func (h *Hash) getWriter(write func(io.Writer)) interface{} {
+	var (
+		champion interface{}
+		value    uint64
+	)
+
+	for _, e := range h.entries {
+		if v := h.hasherWriter.HashWriter(write, e.Value); v > value {
+			champion = e.Member
+			value = v
+		}
+	}
+
+	return champion
+}
+
+// GetInt64 is the same as Get, but accepts the key as an int64 instead of a []byte.
+func (h *Hash) GetInt64(key int64) interface{} {
+	return h.GetUint64(uint64(key))
+}
+
+// GetUint64 is the same as Get, but accepts the key as a uint64 instead of a []byte.
+func (h *Hash) GetUint64(key uint64) interface{} {
+	return h.GetFunc(func(w io.Writer) {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], key)
+		w.Write(buf[:])
+	})
+}
+
+// GetBytes is the same as Get, but accepts the key as one or more byte slices,
+// written to the hasher in order.  This lets callers compose a composite key (e.g.
+// tenant ID + object ID + epoch) without first joining them into a single []byte.
+func (h *Hash) GetBytes(key ...[]byte) interface{} {
+	return h.GetFunc(func(w io.Writer) {
+		for _, k := range key {
+			w.Write(k)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package rendezvous
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"testing"
+)
+
+func TestHashGetBytesMatchesGet(t *testing.T) {
+	h := new(Builder).AddStrings("foo.com", "bar.net", "baz.org").New()
+
+	a := []byte("tenant-1:")
+	b := []byte("object-42")
+	joined := append(append([]byte{}, a...), b...)
+
+	if got, want := h.GetBytes(a, b), h.Get(joined); got != want {
+		t.Errorf("GetBytes(a, b) = %v, want %v", got, want)
+	}
+}
+
+func TestHashGetUint64MatchesGet(t *testing.T) {
+	h := new(Builder).AddStrings("foo.com", "bar.net", "baz.org").New()
+
+	var key uint64 = 112233445566
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], key)
+
+	want := h.Get(buf[:])
+	if got := h.GetUint64(key); got != want {
+		t.Errorf("GetUint64(%d) = %v, want %v", key, got, want)
+	}
+
+	if got := h.GetInt64(int64(key)); got != want {
+		t.Errorf("GetInt64(%d) = %v, want %v", key, got, want)
+	}
+}
+
+func TestHashGetFuncFallsBackForCustomHasher(t *testing.T) {
+	var calls int
+	custom := Hasher(func(key, member []byte) uint64 {
+		calls++
+		return DefaultHasher(key, member)
+	})
+
+	h := new(Builder).Hasher(custom).AddStrings("foo.com", "bar.net").New()
+
+	if h.GetBytes([]byte("mac:"), []byte("112233445566")) == nil {
+		t.Fatal("expected a member")
+	}
+
+	if calls == 0 {
+		t.Error("expected GetBytes to fall back to invoking the custom Hasher")
+	}
+}
+
+func BenchmarkHashGetUint64(b *testing.B) {
+	h := new(Builder).
+		Hash64(fnv.New64a).
+		AddStrings("foo.com", "bar.net", "baz.org").New()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h.GetUint64(uint64(i))
+	}
+}
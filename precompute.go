@@ -0,0 +1,40 @@
+package rendezvous
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+// sumToUint64 reduces a hash.Hash's Sum output to a uint64 by reading its first 8
+// bytes as a big-endian integer, truncating longer sums and padding shorter ones with
+// trailing zero bytes.
+func sumToUint64(sum []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:], sum)
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+// precomputeEntries replaces each entry's Value with memberDigest(hf, Value),
+// computed once, so that restoreHasher's Hasher only has to write the key at lookup
+// time instead of rehashing every member's original bytes on every call.
+func precomputeEntries(entries []Entry, hf func() hash.Hash) []Entry {
+	precomputed := make([]Entry, len(entries))
+	for i, e := range entries {
+		precomputed[i] = Entry{Member: e.Member, Value: memberDigest(hf, e.Value)}
+	}
+
+	return precomputed
+}
+
+// restoreHasher builds a Hasher that combines a key with a member's precomputed
+// digest (passed in as the member argument), instead of rehashing the member's
+// original bytes.  Key bytes are written first, matching NewHasher, NewHasher32, and
+// NewHasher64.
+func restoreHasher(hf func() hash.Hash) Hasher {
+	return func(key, digest []byte) uint64 {
+		h := hf()
+		h.Write(key)
+		h.Write(digest)
+		return sumToUint64(h.Sum(nil))
+	}
+}
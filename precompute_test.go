@@ -0,0 +1,63 @@
+package rendezvous
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"testing"
+)
+
+// sumHash is a minimal, non-cryptographic hash.Hash used to confirm precomputeEntries
+// works with any hash.Hash constructor, not just ones with particular properties.
+type sumHash struct {
+	sum uint64
+}
+
+func newSumHash() hash.Hash { return &sumHash{} }
+
+func (h *sumHash) Write(p []byte) (int, error) {
+	for _, b := range p {
+		h.sum = h.sum*31 + uint64(b)
+	}
+
+	return len(p), nil
+}
+
+func (h *sumHash) Sum(b []byte) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], h.sum)
+	return append(b, buf[:]...)
+}
+
+func (h *sumHash) Reset()         { h.sum = 0 }
+func (h *sumHash) Size() int      { return 8 }
+func (h *sumHash) BlockSize() int { return 1 }
+
+func TestPrecomputeMembersMatchesUnprecomputed(t *testing.T) {
+	ms := []string{"foo.com", "bar.net", "baz.org", "quux.io"}
+
+	plain := new(Builder).Hash(sha256.New).AddStrings(ms...).New()
+	precomputed := new(Builder).Hash(sha256.New).PrecomputeMembers().AddStrings(ms...).New()
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if plain.GetString(key) != precomputed.GetString(key) {
+			t.Fatalf("precomputed hash diverged from plain hash for key %q", key)
+		}
+	}
+}
+
+func TestPrecomputeMembersMatchesUnprecomputedForArbitraryHash(t *testing.T) {
+	ms := []string{"foo.com", "bar.net", "baz.org"}
+
+	plain := new(Builder).Hash(newSumHash).AddStrings(ms...).New()
+	precomputed := new(Builder).Hash(newSumHash).PrecomputeMembers().AddStrings(ms...).New()
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if plain.GetString(key) != precomputed.GetString(key) {
+			t.Fatalf("precomputed hash diverged from plain hash for key %q", key)
+		}
+	}
+}
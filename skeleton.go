@@ -0,0 +1,215 @@
+package rendezvous
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// skeletonPathKey is a fixed key used only to derive a node's id from its path in
+// the tree.  It has no relationship to any lookup key.
+var skeletonPathKey = []byte("rendezvous-skeleton-path")
+
+// skeletonNode is a single node in a skeleton tree.  A node is either an internal
+// node with children, or a leaf node holding the subset of member entries assigned to
+// it.  id is a stable identifier derived from the node's path from the root, and
+// weight is the number of members reachable beneath the node; both depend only on
+// the tree's shape and membership, never on the key being looked up.
+type skeletonNode struct {
+	id       []byte
+	children []*skeletonNode
+	entries  []Entry
+	weight   int
+}
+
+// skeletonTree is the lookup structure built by Builder.Skeleton.  A lookup descends
+// the tree from the root, at each internal node picking a child via a weighted
+// rendezvous hash (see pickChild), then runs a flat rendezvous scan over the winning
+// leaf's entries.  This costs O(fanout * log_fanout(n)) hasher calls, rather than the
+// flat O(n) scan that a plain Hash performs.
+type skeletonTree struct {
+	root   *skeletonNode
+	hasher Hasher
+	count  int
+}
+
+// newSkeletonTree builds a tree shape sized for len(entries) leaves of at most fanout
+// members each, then assigns every entry to a leaf via a flat rendezvous hash over
+// the leaves' ids (see pickLeaf). Shape and leaf ids depend only on the leaf count,
+// ceil(len(entries)/fanout), and fanout, not on which members exist, so while the
+// leaf count stays the same a member's own bytes are the only thing that determines
+// its leaf, and adding or removing one member reassigns no other member.  When adding
+// or removing a member changes the leaf count, the shape and leaf ids change with it
+// and other members can be reassigned to different leaves.
+func newSkeletonTree(entries []Entry, hasher Hasher, fanout int) *skeletonTree {
+	leafCount := (len(entries) + fanout - 1) / fanout
+	if leafCount < 1 {
+		leafCount = 1
+	}
+
+	root, leaves := buildSkeletonShape(hasher, fanout, leafCount, nil)
+
+	for _, e := range entries {
+		leaf := pickLeaf(hasher, e.Value, leaves)
+		leaf.entries = append(leaf.entries, e)
+	}
+
+	weighSkeletonNode(root)
+
+	return &skeletonTree{
+		root:   root,
+		hasher: hasher,
+		count:  len(entries),
+	}
+}
+
+// buildSkeletonShape recursively splits size leaf slots into at most fanout child
+// groups per node, returning the root together with its leaves in path order.  The
+// shape is built before any members are assigned to it, so it never depends on
+// member identity.
+func buildSkeletonShape(hasher Hasher, fanout, size int, path []byte) (*skeletonNode, []*skeletonNode) {
+	if size <= 1 {
+		leaf := &skeletonNode{id: pathID(hasher, path)}
+		return leaf, []*skeletonNode{leaf}
+	}
+
+	if size <= fanout {
+		node := &skeletonNode{id: pathID(hasher, path)}
+		leaves := make([]*skeletonNode, 0, size)
+		for i := 0; i < size; i++ {
+			leaf := &skeletonNode{id: pathID(hasher, childPath(path, byte(i)))}
+			node.children = append(node.children, leaf)
+			leaves = append(leaves, leaf)
+		}
+
+		return node, leaves
+	}
+
+	groupSize := (size + fanout - 1) / fanout
+	node := &skeletonNode{id: pathID(hasher, path)}
+	leaves := make([]*skeletonNode, 0, size)
+
+	for i, remaining := 0, size; remaining > 0; i++ {
+		n := groupSize
+		if n > remaining {
+			n = remaining
+		}
+
+		child, childLeaves := buildSkeletonShape(hasher, fanout, n, childPath(path, byte(i)))
+		node.children = append(node.children, child)
+		leaves = append(leaves, childLeaves...)
+		remaining -= n
+	}
+
+	return node, leaves
+}
+
+// childPath appends a child index to a node's path, copying so the parent's path
+// slice is never mutated by a later sibling.
+func childPath(path []byte, i byte) []byte {
+	p := make([]byte, len(path)+1)
+	copy(p, path)
+	p[len(path)] = i
+	return p
+}
+
+// pathID derives a node's stable identifier by hashing its path from the root,
+// rather than using the short, near-sequential path bytes (0x00, 0x01, ...)
+// directly.  Some hashers (FNV in particular) don't diffuse such similar short inputs
+// well, which would otherwise correlate sibling scores.  Hashing the path first gives
+// siblings as much separation as the hasher itself provides.
+func pathID(hasher Hasher, path []byte) []byte {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, hasher(skeletonPathKey, path))
+	return id
+}
+
+// pickLeaf assigns a member to one of the tree's leaves via a flat rendezvous hash
+// over the leaves' ids, so a member's leaf assignment depends only on the member's
+// own bytes and the tree's shape, never on which other members happen to exist.
+func pickLeaf(hasher Hasher, memberValue []byte, leaves []*skeletonNode) *skeletonNode {
+	var (
+		champion *skeletonNode
+		value    uint64
+	)
+
+	for i, l := range leaves {
+		if v := hasher(memberValue, l.id); i == 0 || v > value {
+			champion = l
+			value = v
+		}
+	}
+
+	return champion
+}
+
+// weighSkeletonNode computes each node's weight -- the count of members reachable
+// beneath it -- bottom-up.  pickChild uses this so that a subtree holding more
+// members is proportionally more likely to be the one a key descends into.
+func weighSkeletonNode(node *skeletonNode) int {
+	if len(node.children) == 0 {
+		node.weight = len(node.entries)
+		return node.weight
+	}
+
+	var total int
+	for _, c := range node.children {
+		total += weighSkeletonNode(c)
+	}
+
+	node.weight = total
+	return total
+}
+
+// get descends the tree to a leaf, then runs a flat rendezvous scan over that leaf's
+// entries to pick the final member.
+func (t *skeletonTree) get(key []byte) interface{} {
+	node := t.root
+	for len(node.children) > 0 {
+		node = pickChild(t.hasher, key, node.children)
+	}
+
+	var (
+		champion interface{}
+		value    uint64
+	)
+
+	for _, e := range node.entries {
+		if v := t.hasher(key, e.Value); v > value {
+			champion = e.Member
+			value = v
+		}
+	}
+
+	return champion
+}
+
+// pickChild runs a weighted rendezvous hash over the children, weighted by each
+// child's member count, to select which subtree a key descends into.  This is the
+// same scoring rule as WeightedHash: score = weight / -log(u), which is what gives
+// weighted rendezvous hashing its defining property -- a child is selected with
+// probability proportional to its share of the total weight. Applied recursively,
+// with weight equal to each subtree's member count, that property is what keeps the
+// tree's overall member distribution and minimal-disruption behavior in line with a
+// flat Hash over the same members, rather than descending by a node id alone with no
+// relationship to what's reachable beneath it.
+func pickChild(hasher Hasher, key []byte, children []*skeletonNode) *skeletonNode {
+	var (
+		champion *skeletonNode
+		best     float64
+	)
+
+	for _, c := range children {
+		if c.weight == 0 {
+			continue
+		}
+
+		u := (float64(hasher(key, c.id)) + 1) / twoTo64
+		score := float64(c.weight) / -math.Log(u)
+		if champion == nil || score > best {
+			champion = c
+			best = score
+		}
+	}
+
+	return champion
+}
@@ -0,0 +1,102 @@
+package rendezvous
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func ExampleBuilder_Skeleton() {
+	h := new(Builder).
+		AddStrings("foo.com", "bar.net", "baz.org", "quux.io").
+		Skeleton(2)
+
+	fmt.Println(h.GetString("mac:112233445566"))
+	// Output: baz.org
+}
+
+// members generates n distinguishable member names.  The index is run through a
+// multiplicative hash rather than used as a plain decimal suffix, since FNV-1a (the
+// default Hasher) diffuses short, near-identical byte sequences poorly, which would
+// otherwise skew even a flat Hash's distribution over "member-0".."member-N" long
+// before the skeleton tree is involved.
+func members(n int) []string {
+	ms := make([]string, n)
+	for i := range ms {
+		ms[i] = fmt.Sprintf("member-%08x", uint32(i)*2654435761)
+	}
+
+	return ms
+}
+
+func TestSkeletonMatchesFlatDistribution(t *testing.T) {
+	const (
+		keyCount  = 20000
+		tolerance = 2.0
+	)
+
+	ms := members(500)
+
+	flat := new(Builder)
+	skeleton := new(Builder)
+	for _, m := range ms {
+		flat.AddStrings(m)
+		skeleton.AddStrings(m)
+	}
+
+	flatHash := flat.New()
+	skeletonHash := skeleton.Skeleton(8)
+
+	flatCounts := make(map[string]int, len(ms))
+	skeletonCounts := make(map[string]int, len(ms))
+
+	for i := 0; i < keyCount; i++ {
+		key := strconv.Itoa(i)
+		flatCounts[flatHash.GetString(key).(string)]++
+		skeletonCounts[skeletonHash.GetString(key).(string)]++
+	}
+
+	expected := float64(keyCount) / float64(len(ms))
+	for _, m := range ms {
+		flatRatio := float64(flatCounts[m]) / expected
+		skeletonRatio := float64(skeletonCounts[m]) / expected
+		if math.Abs(flatRatio-skeletonRatio) > tolerance {
+			t.Errorf("member %s: flat load ratio %f diverges from skeleton load ratio %f", m, flatRatio, skeletonRatio)
+		}
+	}
+}
+
+func TestSkeletonMinimalDisruption(t *testing.T) {
+	const keyCount = 20000
+
+	ms := members(500)
+
+	before := new(Builder)
+	for _, m := range ms {
+		before.AddStrings(m)
+	}
+
+	beforeHash := before.Skeleton(8)
+
+	after := new(Builder)
+	for _, m := range ms[:len(ms)-1] {
+		after.AddStrings(m)
+	}
+
+	afterHash := after.Skeleton(8)
+
+	var moved int
+	for i := 0; i < keyCount; i++ {
+		key := strconv.Itoa(i)
+		if beforeHash.GetString(key) != afterHash.GetString(key) {
+			moved++
+		}
+	}
+
+	expected := 1.0 / float64(len(ms))
+	actual := float64(moved) / float64(keyCount)
+	if actual > expected*3 || actual < expected*0.3 {
+		t.Errorf("expected roughly %f of keys to move, got %f", expected, actual)
+	}
+}
@@ -0,0 +1,145 @@
+package rendezvous
+
+import (
+	"hash"
+	"math"
+)
+
+// twoTo64 is 2^64 expressed as a float64.  It's used to normalize a hasher's uint64
+// output into the open interval (0,1) required by the weighted scoring function.
+var twoTo64 = math.Exp2(64)
+
+// WeightedEntry is a tuple containing the member object, its hash value, and its
+// relative weight within a WeightedHash.
+type WeightedEntry struct {
+	// Member is the object returned by the rendezvous hash for a given key
+	Member interface{}
+
+	// Value is the hash value of the member
+	Value []byte
+
+	// Weight is the relative capacity of this member.  Weights need not sum to any
+	// particular total; only the ratio between members' weights affects placement.
+	Weight float64
+}
+
+// WeightedHash implements a weighted rendezvous hash over a set of members.  Unlike
+// Hash, members with larger weights are selected more often, in proportion to their
+// weight relative to the total weight of all members.  A WeightedHash instance is
+// safe for concurrent reads and writes.  It is immutable once created by a
+// WeightedBuilder.
+//
+// Scores are computed using the standard log-based weighted rendezvous formula:
+// for each member, the hasher's uint64 output is normalized to u in (0,1), and the
+// member's score is weight / -math.Log(u).  The member with the largest score wins.
+type WeightedHash struct {
+	entries []WeightedEntry
+	hasher  Hasher
+}
+
+// Len returns the number of entries in the weighted rendezvous hash table.  If this
+// method returns 0, all methods that return members will return nil.
+func (h *WeightedHash) Len() int {
+	return len(h.entries)
+}
+
+// Get returns the result of a weighted rendezvous hash given an arbitrary key
+func (h *WeightedHash) Get(key []byte) interface{} {
+	if len(h.entries) == 0 {
+		return nil
+	}
+
+	return h.get(key)
+}
+
+// GetString returns the result of a weighted rendezvous hash using a string key
+func (h *WeightedHash) GetString(key string) interface{} {
+	if len(h.entries) == 0 {
+		// be kind to the gc: avoid an extra byte slice if we're empty anyway
+		return nil
+	}
+
+	return h.get([]byte(key))
+}
+
+func (h *WeightedHash) get(key []byte) interface{} {
+	var (
+		champion interface{}
+		score    = math.Inf(-1)
+	)
+
+	for _, e := range h.entries {
+		u := (float64(h.hasher(key, e.Value)) + 1) / twoTo64
+		if s := e.Weight / -math.Log(u); s > score {
+			champion = e.Member
+			score = s
+		}
+	}
+
+	return champion
+}
+
+var emptyWeightedHash = WeightedHash{hasher: DefaultHasher}
+
+// EmptyWeightedHash returns the canonicalized empty WeightedHash instance.  This is
+// used mainly by WeightedBuilder when no entries have been added.
+func EmptyWeightedHash() *WeightedHash {
+	return &emptyWeightedHash
+}
+
+// WeightedBuilder is a mutable, fluent builder for WeightedHash instances.
+// WeightedBuilders are not safe for concurrent reads and writes.  The zero value for
+// this struct is a valid instance.
+type WeightedBuilder struct {
+	entries []WeightedEntry
+	hasher  Hasher
+}
+
+// Hasher sets the Hasher strategy for the next WeightedHash created by this builder.
+// By default, DefaultHasher is used.
+func (b *WeightedBuilder) Hasher(h Hasher) *WeightedBuilder {
+	b.hasher = h
+	return b
+}
+
+// Hash32 uses a 32-bit hashing constructor as the hash algorithm
+func (b *WeightedBuilder) Hash32(hf func() hash.Hash32) *WeightedBuilder {
+	return b.Hasher(NewHasher32(hf))
+}
+
+// Hash64 uses a 64-bit hashing constructor as the hash algorithm
+func (b *WeightedBuilder) Hash64(hf func() hash.Hash64) *WeightedBuilder {
+	return b.Hasher(NewHasher64(hf))
+}
+
+// Add appends entries to the final WeightedHash
+func (b *WeightedBuilder) Add(e ...WeightedEntry) *WeightedBuilder {
+	b.entries = append(b.entries, e...)
+	return b
+}
+
+// AddWeightedMember appends a single member together with its hash value and weight
+func (b *WeightedBuilder) AddWeightedMember(m interface{}, v []byte, weight float64) *WeightedBuilder {
+	return b.Add(WeightedEntry{Member: m, Value: v, Weight: weight})
+}
+
+// New creates a WeightedHash using this WeightedBuilder's current configuration.
+// This builder is reset prior to returning.
+func (b *WeightedBuilder) New() *WeightedHash {
+	if len(b.entries) == 0 {
+		return EmptyWeightedHash()
+	}
+
+	h := &WeightedHash{
+		entries: b.entries,
+		hasher:  b.hasher,
+	}
+
+	if h.hasher == nil {
+		h.hasher = DefaultHasher
+	}
+
+	b.entries = nil
+	b.hasher = nil
+	return h
+}
@@ -0,0 +1,98 @@
+package rendezvous
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func ExampleWeightedHash() {
+	h := new(WeightedBuilder).
+		AddWeightedMember("foo.com", []byte("foo.com"), 1).
+		AddWeightedMember("bar.net", []byte("bar.net"), 1).New()
+
+	fmt.Println(h.GetString("mac:112233445566"))
+	// Output: foo.com
+}
+
+func TestWeightedHashDistribution(t *testing.T) {
+	const (
+		keyCount  = 100000
+		tolerance = 0.03
+	)
+
+	weights := map[string]float64{
+		"foo.com": 1,
+		"bar.net": 2,
+		"baz.org": 5,
+	}
+
+	var total float64
+	b := new(WeightedBuilder)
+	for m, w := range weights {
+		b.AddWeightedMember(m, []byte(m), w)
+		total += w
+	}
+
+	h := b.New()
+	counts := make(map[string]int, len(weights))
+	for i := 0; i < keyCount; i++ {
+		key := strconv.Itoa(i)
+		member := h.GetString(key).(string)
+		counts[member]++
+	}
+
+	for m, w := range weights {
+		expected := w / total
+		actual := float64(counts[m]) / float64(keyCount)
+		if math.Abs(expected-actual) > tolerance {
+			t.Errorf("member %s: expected load ratio ~%f, got %f", m, expected, actual)
+		}
+	}
+}
+
+func TestWeightedHashMinimalDisruption(t *testing.T) {
+	const keyCount = 100000
+
+	weights := map[string]float64{
+		"foo.com": 1,
+		"bar.net": 1,
+		"baz.org": 1,
+		"quux.io": 1,
+	}
+
+	var total float64
+	before := new(WeightedBuilder)
+	for m, w := range weights {
+		before.AddWeightedMember(m, []byte(m), w)
+		total += w
+	}
+
+	beforeHash := before.New()
+
+	after := new(WeightedBuilder)
+	for m, w := range weights {
+		if m == "quux.io" {
+			continue
+		}
+
+		after.AddWeightedMember(m, []byte(m), w)
+	}
+
+	afterHash := after.New()
+
+	var moved int
+	for i := 0; i < keyCount; i++ {
+		key := strconv.Itoa(i)
+		if beforeHash.GetString(key) != afterHash.GetString(key) {
+			moved++
+		}
+	}
+
+	expected := weights["quux.io"] / total
+	actual := float64(moved) / float64(keyCount)
+	if math.Abs(expected-actual) > 0.03 {
+		t.Errorf("expected roughly %f of keys to move, got %f", expected, actual)
+	}
+}